@@ -3,9 +3,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Devon-White/link_checker/internal/checker"
+	"github.com/Devon-White/link_checker/internal/metrics"
 	"github.com/Devon-White/link_checker/internal/sitemap"
 	"github.com/spf13/cobra"
 )
@@ -24,6 +28,13 @@ var (
 	lycheeConfig string
 	dryRun       bool
 	perSitemap   bool
+	metricsAddr        string
+	metricsPushgateway string
+	metricsJob         string
+	engine             string
+	cacheFile    string
+	cacheTTL     time.Duration
+	onlyChanged  bool
 )
 
 func main() {
@@ -41,7 +52,7 @@ Example:
 	}
 
 	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write report to file")
-	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "compact", "Output format: compact, json, markdown")
+	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "compact", "Output format: compact, json, markdown, sarif, junit (json is linkaudit's own report schema, not the underlying engine's native output)")
 	rootCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 20, "Maximum concurrent requests for lychee")
 	rootCmd.Flags().IntVarP(&timeout, "timeout", "t", 30, "Request timeout in seconds")
 	rootCmd.Flags().StringArrayVarP(&excludes, "exclude", "e", nil, "Exclude URLs matching pattern (can be repeated)")
@@ -50,6 +61,13 @@ Example:
 	rootCmd.Flags().StringVar(&lycheeConfig, "config", "", "Path to lychee config file")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Fetch sitemap and list URLs without checking links")
 	rootCmd.Flags().BoolVar(&perSitemap, "per-sitemap", false, "Report results grouped by source sitemap")
+	rootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Address to serve Prometheus metrics on for ad-hoc local inspection (e.g. :9090); disabled if empty. Exits with the process, so a scheduled job should use --metrics-pushgateway instead")
+	rootCmd.Flags().StringVar(&metricsPushgateway, "metrics-pushgateway", "", "Pushgateway URL to push metrics to after the run completes (e.g. http://pushgateway:9091); disabled if empty")
+	rootCmd.Flags().StringVar(&metricsJob, "metrics-job", "linkaudit", "Job name to push metrics under when --metrics-pushgateway is set")
+	rootCmd.Flags().StringVar(&engine, "engine", "lychee", "Link checking engine: lychee, native")
+	rootCmd.Flags().StringVar(&cacheFile, "cache", "", "Path to a cache file for resumable audits; disabled if empty")
+	rootCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", time.Hour, "How long a cached link result is considered fresh")
+	rootCmd.Flags().BoolVar(&onlyChanged, "only-changed", false, "Skip cached successes unless the page's sitemap <lastmod> is newer")
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -59,27 +77,61 @@ Example:
 func run(cmd *cobra.Command, args []string) error {
 	baseURL := args[0]
 
-	// Check lychee is installed (skip for dry-run)
-	if !dryRun && !checker.IsLycheeInstalled() {
+	if err := checker.ValidateEngine(engine); err != nil {
+		return err
+	}
+
+	// Check lychee is installed (skip for dry-run or the native engine)
+	if !dryRun && !strings.EqualFold(engine, "native") && !checker.IsLycheeInstalled() {
 		return fmt.Errorf("lychee is not installed. Install it from: https://github.com/lycheeverse/lychee")
 	}
 
-	// Step 1: Fetch sitemap (supports both sitemap.xml and sitemap index)
-	sitemapURL := baseURL
-	fmt.Printf("Fetching sitemap from %s...\n", sitemapURL)
+	if metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(metricsAddr); err != nil {
+				fmt.Fprintf(os.Stderr, "metrics server stopped: %v\n", err)
+			}
+		}()
+		fmt.Printf("Serving metrics on %s/metrics\n", metricsAddr)
+	}
+
+	var cache *checker.Cache
+	if cacheFile != "" {
+		c, err := checker.OpenCache(cacheFile)
+		if err != nil {
+			return fmt.Errorf("failed to open cache: %w", err)
+		}
+		defer c.Close()
+		cache = c
+	}
+
+	// Step 1: Locate the sitemap. A bare site root gets robots.txt-aware
+	// discovery; a direct sitemap/sitemap-index URL is fetched as-is.
+	fmt.Printf("Fetching sitemap from %s...\n", baseURL)
 
-	fetchResult, err := sitemap.FetchGrouped(sitemapURL)
+	fetchResult, err := locateSitemap(baseURL)
 	if err != nil {
 		return fmt.Errorf("failed to fetch sitemap: %w", err)
 	}
 
-	fmt.Printf("Found %d pages in %d sitemap(s)\n\n", len(fetchResult.AllURLs), len(fetchResult.Sitemaps))
+	fmt.Printf("Found %d pages in %d sitemap(s)\n", len(fetchResult.AllURLs), len(fetchResult.Sitemaps))
+	if len(fetchResult.Errors) > 0 {
+		fmt.Printf("%d sitemap(s) failed to fetch:\n", len(fetchResult.Errors))
+		for _, sErr := range fetchResult.Errors {
+			fmt.Printf("  %s\n", sErr.Error())
+		}
+	}
+	fmt.Println()
 
 	// Dry run: just list URLs and exit
 	if dryRun {
 		if perSitemap {
 			for smURL, urls := range fetchResult.Sitemaps {
-				fmt.Printf("=== %s (%d URLs) ===\n", smURL, len(urls))
+				source := fetchResult.Sources[smURL]
+				if source == "" {
+					source = "direct"
+				}
+				fmt.Printf("=== %s (%d URLs, discovered via %s) ===\n", smURL, len(urls), source)
 				for _, u := range urls {
 					fmt.Println(u)
 				}
@@ -95,7 +147,7 @@ func run(cmd *cobra.Command, args []string) error {
 
 	// Per-sitemap mode: check each sitemap separately
 	if perSitemap {
-		return runPerSitemap(fetchResult)
+		return runPerSitemap(fetchResult, cache)
 	}
 
 	// Merged mode: check all URLs together
@@ -108,6 +160,11 @@ func run(cmd *cobra.Command, args []string) error {
 		Format:      outputFormat,
 		OutputFile:  outputFile,
 		Verbose:     verbose,
+		Engine:      engine,
+		Cache:       cache,
+		CacheTTL:    cacheTTL,
+		OnlyChanged: onlyChanged,
+		LastMod:     fetchResult.LastMod,
 	})
 	if err != nil {
 		return fmt.Errorf("link check failed: %w", err)
@@ -119,6 +176,8 @@ func run(cmd *cobra.Command, args []string) error {
 			len(fetchResult.AllURLs), result.PassedCount, result.FailedCount, result.ExcludedCount)
 	}
 
+	pushMetricsIfConfigured()
+
 	// Exit with error code if broken links found
 	if result.FailedCount > 0 {
 		os.Exit(2)
@@ -127,6 +186,41 @@ func run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// pushMetricsIfConfigured pushes the run's metrics to --metrics-pushgateway,
+// if set, logging rather than failing the run on a push error. It's called
+// once a link check has finished in run/runPerSitemap, right before the
+// broken-link exit path, which uses os.Exit directly and would otherwise
+// skip a deferred push. Dry runs and early error returns don't push.
+func pushMetricsIfConfigured() {
+	if metricsPushgateway == "" {
+		return
+	}
+	if err := metrics.Push(metricsPushgateway, metricsJob); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to push metrics to %s: %v\n", metricsPushgateway, err)
+	}
+}
+
+// locateSitemap fetches sitemapURL directly if it looks like a sitemap file
+// (ends in .xml or .xml.gz), runs robots.txt-aware discovery if it's a bare
+// site root (no path, or just "/"), and otherwise rejects the URL rather
+// than silently discarding an unrecognized path.
+func locateSitemap(sitemapURL string) (*sitemap.FetchResult, error) {
+	parsed, err := url.Parse(sitemapURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	path := strings.ToLower(parsed.Path)
+	switch {
+	case strings.HasSuffix(path, ".xml") || strings.HasSuffix(path, ".xml.gz"):
+		return sitemap.FetchGrouped(sitemapURL)
+	case path == "" || path == "/":
+		return sitemap.Discover(sitemapURL)
+	default:
+		return nil, fmt.Errorf("%q doesn't look like a sitemap URL (.xml/.xml.gz) or a site root (no path) — pass either a direct sitemap URL or a bare site root for robots.txt-aware discovery", sitemapURL)
+	}
+}
+
 // SitemapReport represents the check results for a single sitemap
 type SitemapReport struct {
 	SitemapURL string          `json:"sitemap_url"`
@@ -142,7 +236,7 @@ type FullReport struct {
 	TotalFailed int             `json:"total_failed"`
 }
 
-func runPerSitemap(fetchResult *sitemap.FetchResult) error {
+func runPerSitemap(fetchResult *sitemap.FetchResult, cache *checker.Cache) error {
 	report := FullReport{
 		Sitemaps: make([]SitemapReport, 0, len(fetchResult.Sitemaps)),
 	}
@@ -160,6 +254,11 @@ func runPerSitemap(fetchResult *sitemap.FetchResult) error {
 			ConfigFile:  lycheeConfig,
 			Format:      "json",
 			Verbose:     verbose,
+			Engine:      engine,
+			Cache:       cache,
+			CacheTTL:    cacheTTL,
+			OnlyChanged: onlyChanged,
+			LastMod:     fetchResult.LastMod,
 		})
 		if err != nil {
 			fmt.Printf("  Error: %v\n", err)
@@ -189,6 +288,8 @@ func runPerSitemap(fetchResult *sitemap.FetchResult) error {
 	fmt.Printf("Total pages: %d | Passed: %d | Failed: %d\n",
 		report.TotalPages, report.TotalPassed, report.TotalFailed)
 
+	pushMetricsIfConfigured()
+
 	if outputFile != "" {
 		data, err := json.MarshalIndent(report, "", "  ")
 		if err != nil {
@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var cacheBucket = []byte("links")
+
+// CacheEntry is the persisted record for a single checked link.
+type CacheEntry struct {
+	Status       string    `json:"status"`
+	Code         int       `json:"code"`
+	SourceURL    string    `json:"source_url"`
+	CheckedAt    time.Time `json:"checked_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+}
+
+// Cache persists link check results on disk so repeat audits of the same
+// sitemap can skip re-checking links that were verified recently.
+type Cache struct {
+	db *bolt.DB
+}
+
+// OpenCache opens (creating if necessary) a Cache file at path.
+func OpenCache(path string) (*Cache, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache file: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(cacheBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	return &Cache{db: db}, nil
+}
+
+// Close releases the underlying cache file.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Split partitions urls — extracted link URLs, not sitemap page URLs; the
+// cache is keyed per link — into a Result built from fresh cache entries
+// and a slice of URLs that still need checking. An entry is fresh when
+// it's within ttl or, in onlyChanged mode, when it last passed and the
+// page's sitemap lastmod (if known) is no newer than the entry's checked-at
+// time; ttl is not applied in onlyChanged mode.
+func (c *Cache) Split(urls []string, lastmod map[string]time.Time, ttl time.Duration, onlyChanged bool) (*Result, []string) {
+	fresh := &Result{}
+	var stale []string
+
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheBucket)
+		for _, u := range urls {
+			entry, ok := lookupEntry(b, u)
+			if !ok || !isFresh(entry, lastmod[u], ttl, onlyChanged) {
+				stale = append(stale, u)
+				continue
+			}
+
+			fresh.Links = append(fresh.Links, LinkStatus{
+				URL: u, Status: entry.Status, Code: entry.Code, SourceURL: entry.SourceURL,
+				ETag: entry.ETag, LastModified: entry.LastModified,
+			})
+			switch classifyLink(entry.Status, entry.Code) {
+			case "failed":
+				fresh.FailedCount++
+			case "excluded":
+				fresh.ExcludedCount++
+			default:
+				fresh.PassedCount++
+			}
+		}
+		return nil
+	})
+
+	return fresh, stale
+}
+
+func lookupEntry(b *bolt.Bucket, url string) (CacheEntry, bool) {
+	raw := b.Get([]byte(url))
+	if raw == nil {
+		return CacheEntry{}, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// isFresh reports whether entry is recent enough to reuse instead of
+// re-checking. Under onlyChanged, a failed/error entry is never fresh —
+// only successes are gated by the lastmod comparison, regardless of ttl —
+// so a previously broken link gets re-checked every run until it's
+// confirmed fixed, instead of being skipped for as long as its page's
+// lastmod doesn't move. Otherwise an entry is fresh within ttl; a zero ttl
+// never expires.
+func isFresh(entry CacheEntry, lastmod time.Time, ttl time.Duration, onlyChanged bool) bool {
+	if onlyChanged {
+		if classifyLink(entry.Status, entry.Code) == "failed" {
+			return false
+		}
+		return lastmod.IsZero() || !lastmod.After(entry.CheckedAt)
+	}
+	if ttl > 0 && time.Since(entry.CheckedAt) > ttl {
+		return false
+	}
+	return true
+}
+
+// Store persists every link in result, stamped with the current time.
+func (c *Cache) Store(result *Result) error {
+	if result == nil || len(result.Links) == 0 {
+		return nil
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheBucket)
+		for _, link := range result.Links {
+			entry := CacheEntry{
+				Status:       link.Status,
+				Code:         link.Code,
+				SourceURL:    link.SourceURL,
+				CheckedAt:    time.Now(),
+				ETag:         link.ETag,
+				LastModified: link.LastModified,
+			}
+			raw, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("failed to marshal cache entry for %s: %w", link.URL, err)
+			}
+			if err := b.Put([]byte(link.URL), raw); err != nil {
+				return fmt.Errorf("failed to store cache entry for %s: %w", link.URL, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestCache(t *testing.T) *Cache {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cache.bolt")
+	cache, err := OpenCache(path)
+	if err != nil {
+		t.Fatalf("OpenCache: %v", err)
+	}
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
+
+func TestCache_StoreAndSplit(t *testing.T) {
+	cache := openTestCache(t)
+
+	result := &Result{
+		PassedCount: 1,
+		Links:       []LinkStatus{{URL: "https://example.com/a", Status: "ok", Code: 200, SourceURL: "https://example.com/"}},
+	}
+	if err := cache.Store(result); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	fresh, stale := cache.Split([]string{"https://example.com/a", "https://example.com/b"}, nil, time.Hour, false)
+	if len(stale) != 1 || stale[0] != "https://example.com/b" {
+		t.Fatalf("expected only the uncached URL to be stale, got %v", stale)
+	}
+	if len(fresh.Links) != 1 || fresh.Links[0].URL != "https://example.com/a" {
+		t.Fatalf("expected the cached URL in fresh results, got %v", fresh.Links)
+	}
+	if fresh.PassedCount != 1 {
+		t.Errorf("expected PassedCount 1, got %d", fresh.PassedCount)
+	}
+}
+
+func TestCache_StorePreservesETagAndLastModified(t *testing.T) {
+	cache := openTestCache(t)
+
+	result := &Result{
+		Links: []LinkStatus{{
+			URL: "https://example.com/a", Status: "ok", Code: 200,
+			ETag: `"abc123"`, LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		}},
+	}
+	if err := cache.Store(result); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	fresh, _ := cache.Split([]string{"https://example.com/a"}, nil, time.Hour, false)
+	if len(fresh.Links) != 1 {
+		t.Fatalf("expected 1 cached link, got %d", len(fresh.Links))
+	}
+	if fresh.Links[0].ETag != `"abc123"` {
+		t.Errorf("expected ETag to round-trip through the cache, got %q", fresh.Links[0].ETag)
+	}
+	if fresh.Links[0].LastModified != "Wed, 21 Oct 2015 07:28:00 GMT" {
+		t.Errorf("expected LastModified to round-trip through the cache, got %q", fresh.Links[0].LastModified)
+	}
+}
+
+func TestCache_OnlyChangedAlwaysRetriesFailures(t *testing.T) {
+	cache := openTestCache(t)
+
+	result := &Result{Links: []LinkStatus{{URL: "https://example.com/broken", Status: "failed", Code: 404}}}
+	if err := cache.Store(result); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	// Even with no lastmod info at all (nil map, so no page ever looks
+	// "changed"), a failed entry must still come back stale under
+	// onlyChanged so a fix is caught promptly instead of being skipped
+	// forever.
+	_, stale := cache.Split([]string{"https://example.com/broken"}, nil, time.Hour, true)
+	if len(stale) != 1 {
+		t.Fatalf("expected a cached failure to always be re-checked under onlyChanged, got fresh")
+	}
+}
+
+func TestCache_TTLExpiry(t *testing.T) {
+	cache := openTestCache(t)
+
+	result := &Result{Links: []LinkStatus{{URL: "https://example.com/a", Status: "ok", Code: 200}}}
+	if err := cache.Store(result); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	// A zero TTL disables expiry, so check with a 1-millisecond TTL after
+	// a real sleep that outlasts it.
+	time.Sleep(2 * time.Millisecond)
+	_, stale := cache.Split([]string{"https://example.com/a"}, nil, time.Millisecond, false)
+	if len(stale) != 1 {
+		t.Fatalf("expected the entry to be stale once past its TTL, got fresh")
+	}
+}
+
+func TestCache_OnlyChangedGating(t *testing.T) {
+	cache := openTestCache(t)
+
+	result := &Result{Links: []LinkStatus{{URL: "https://example.com/a", Status: "ok", Code: 200}}}
+	if err := cache.Store(result); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	// lastmod older than the cached check: still fresh.
+	older := map[string]time.Time{"https://example.com/a": time.Now().Add(-time.Hour)}
+	_, stale := cache.Split([]string{"https://example.com/a"}, older, time.Hour, true)
+	if len(stale) != 0 {
+		t.Fatalf("expected entry to stay fresh when lastmod predates the cached check, got stale %v", stale)
+	}
+
+	// lastmod newer than the cached check: must be treated as stale.
+	newer := map[string]time.Time{"https://example.com/a": time.Now().Add(time.Hour)}
+	_, stale = cache.Split([]string{"https://example.com/a"}, newer, time.Hour, true)
+	if len(stale) != 1 {
+		t.Fatalf("expected entry to become stale when lastmod is newer than the cached check")
+	}
+}
@@ -1,12 +1,12 @@
 package checker
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Devon-White/link_checker/internal/metrics"
 )
 
 // Options configures the link checker
@@ -19,6 +19,22 @@ type Options struct {
 	Format      string
 	OutputFile  string
 	Verbose     bool
+
+	// Engine selects the checking engine: "lychee" (default) or "native".
+	Engine string
+
+	// Cache, when set, is consulted and updated by the engine on a
+	// per-link basis (the cache is keyed by extracted link URL, not by
+	// sitemap page URL, since those are different URL universes).
+	Cache *Cache
+	// CacheTTL is how long a cached result is considered fresh.
+	CacheTTL time.Duration
+	// OnlyChanged skips cached successes entirely unless the page's
+	// sitemap <lastmod> is newer than the cached check time.
+	OnlyChanged bool
+	// LastMod maps a page URL to its sitemap <lastmod>, used by
+	// OnlyChanged. Populated from sitemap.FetchResult.LastMod.
+	LastMod map[string]time.Time
 }
 
 // LinkStatus represents the status of a checked link
@@ -27,6 +43,12 @@ type LinkStatus struct {
 	Status    string `json:"status"`
 	Code      int    `json:"code,omitempty"`
 	SourceURL string `json:"source_url"`
+	// ETag and LastModified are the response headers of the same name,
+	// captured by NativeEngine so Cache entries can carry them. Lychee's
+	// output doesn't expose response headers, so LycheeEngine leaves
+	// these blank.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
 }
 
 // Result contains the check results
@@ -37,188 +59,138 @@ type Result struct {
 	Links         []LinkStatus `json:"links"`
 }
 
-// LycheeLink represents a single link in lychee output
-type LycheeLink struct {
-	URL    string `json:"url"`
-	Status struct {
-		Text string `json:"text"`
-		Code int    `json:"code"`
-	} `json:"status"`
-}
-
-// LycheeOutput represents the JSON output from lychee
-type LycheeOutput struct {
-	Total       int                       `json:"total"`
-	Successful  int                       `json:"successful"`
-	Errors      int                       `json:"errors"`
-	Excludes    int                       `json:"excludes"`
-	SuccessMap  map[string][]LycheeLink   `json:"success_map"`
-	ErrorMap    map[string][]LycheeLink   `json:"error_map"`
-	ExcludedMap map[string][]LycheeLink   `json:"excluded_map"`
+// Engine checks a set of page URLs for broken links and produces a Result.
+// LycheeEngine shells out to lychee; NativeEngine checks links in-process.
+// An Engine that honors opts.Cache is responsible for consulting and
+// updating it itself, keyed by each extracted link's URL: only the engine
+// sees that URL, after crawling pages whose URLs live in a different
+// namespace entirely.
+type Engine interface {
+	Check(pageURLs []string, opts Options) (*Result, error)
 }
 
-// CheckURLs runs lychee to check all links on the given page URLs
-func CheckURLs(pageURLs []string, opts Options) (*Result, error) {
-	if len(pageURLs) == 0 {
-		return &Result{}, nil
-	}
-
-	// Create temp directory for lychee files
-	tempDir, err := os.MkdirTemp("", "linkaudit-*")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp dir: %w", err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	// Write URLs to temp file for lychee to read
-	urlsFile := filepath.Join(tempDir, "urls.txt")
-	if err := os.WriteFile(urlsFile, []byte(strings.Join(pageURLs, "\n")), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write urls file: %w", err)
-	}
-
-	// Determine output format and file
-	format := opts.Format
-	if format == "" || format == "compact" {
-		format = "compact"
-	}
+// engineNames are the values accepted by Options.Engine and the --engine
+// flag, in the order they should be listed in error messages.
+var engineNames = []string{"lychee", "native"}
 
-	// For JSON output or when we need to parse results, use JSON internally
-	jsonOutputFile := filepath.Join(tempDir, "output.json")
-	
-	// Build lychee command
-	args := []string{
-		"--max-concurrency", fmt.Sprintf("%d", opts.Concurrency),
-		"--timeout", fmt.Sprintf("%d", opts.Timeout),
-		"--files-from", urlsFile,
+// ValidateEngine reports an error if name isn't a recognized engine. An
+// empty name is valid and selects the default (lychee).
+func ValidateEngine(name string) error {
+	if name == "" {
+		return nil
 	}
-
-	// Always output JSON so we can parse results
-	args = append(args, "--format", "json", "--output", jsonOutputFile)
-
-	if opts.NoProgress {
-		args = append(args, "--no-progress")
+	for _, n := range engineNames {
+		if strings.EqualFold(name, n) {
+			return nil
+		}
 	}
+	return fmt.Errorf("unknown engine %q (expected one of: %s)", name, strings.Join(engineNames, ", "))
+}
 
-	if opts.ConfigFile != "" {
-		args = append(args, "--config", opts.ConfigFile)
+// classifyLink buckets a link's status/code into "passed", "failed", or
+// "excluded" — the single rule NativeEngine's tallying and the cache's
+// tallying and freshness bookkeeping use to agree on what counts as a
+// failure. LycheeEngine takes its counts from lychee's own output instead.
+func classifyLink(status string, code int) string {
+	switch {
+	case status == "excluded":
+		return "excluded"
+	case code >= 400 || status == "error" || status == "failed":
+		return "failed"
+	default:
+		return "passed"
 	}
+}
 
-	for _, exclude := range opts.Excludes {
-		args = append(args, "--exclude", exclude)
+// selectEngine returns the Engine named by opts.Engine, defaulting to
+// LycheeEngine when unset. Callers should validate opts.Engine with
+// ValidateEngine first; an unrecognized name here still falls back to
+// lychee rather than panicking.
+func selectEngine(opts Options) Engine {
+	switch strings.ToLower(opts.Engine) {
+	case "native":
+		return &NativeEngine{}
+	default:
+		return &LycheeEngine{}
 	}
+}
 
-	if opts.Verbose {
-		fmt.Printf("Running: lychee %s\n", strings.Join(args, " "))
+// CheckURLs checks all pageURLs for broken links using the engine selected
+// by opts.Engine (lychee by default). Every page is always fetched/crawled
+// — pages and extracted links are different URL universes, so pages can't
+// be pre-filtered by the (per-link) cache without silently losing coverage
+// for pages that happen to also be linked from elsewhere. When opts.Cache
+// is set, the engine consults and updates it per extracted link instead.
+func CheckURLs(pageURLs []string, opts Options) (*Result, error) {
+	if len(pageURLs) == 0 {
+		return &Result{}, nil
 	}
 
-	// Run lychee
-	cmd := exec.Command("lychee", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run and capture exit code (lychee exits 2 on broken links, which is not an error)
-	_ = cmd.Run()
-
-	// Parse JSON output
-	result := &Result{}
+	metrics.PagesTotal.Add(float64(len(pageURLs)))
 
-	outputBytes, err := os.ReadFile(jsonOutputFile)
+	engine := selectEngine(opts)
+	result, err := engine.Check(pageURLs, opts)
 	if err != nil {
-		// If no output, assume success
-		result.PassedCount = len(pageURLs)
-		return result, nil
+		return nil, err
 	}
 
-	var output LycheeOutput
-	if err := json.Unmarshal(outputBytes, &output); err != nil {
-		return nil, fmt.Errorf("failed to parse lychee output: %w", err)
-	}
+	recordLinkMetrics(result)
 
-	result.PassedCount = output.Successful
-	result.FailedCount = output.Errors
-	result.ExcludedCount = output.Excludes
-
-	// Collect all links with their status and source
-	for sourceURL, links := range output.SuccessMap {
-		for _, link := range links {
-			result.Links = append(result.Links, LinkStatus{
-				URL:       link.URL,
-				Status:    link.Status.Text,
-				Code:      link.Status.Code,
-				SourceURL: sourceURL,
-			})
+	if opts.OutputFile != "" {
+		if err := writeReport(result, opts, len(pageURLs)); err != nil {
+			return nil, err
 		}
 	}
 
-	for sourceURL, links := range output.ErrorMap {
-		for _, link := range links {
-			result.Links = append(result.Links, LinkStatus{
-				URL:       link.URL,
-				Status:    link.Status.Text,
-				Code:      link.Status.Code,
-				SourceURL: sourceURL,
-			})
+	return result, nil
+}
+
+// recordLinkMetrics reports pass/fail counts for a finished check to the
+// metrics package. Cached results are counted too, since the Prometheus
+// series tracks link health over time, not engine invocations.
+func recordLinkMetrics(result *Result) {
+	for _, link := range result.Links {
+		switch link.Status {
+		case "excluded":
+			continue
+		default:
+			if link.Code >= 400 || link.Status == "error" {
+				metrics.LinksFailedTotal.WithLabelValues(strconv.Itoa(link.Code)).Inc()
+			} else {
+				metrics.LinksPassedTotal.Inc()
+			}
 		}
 	}
+}
 
-	for sourceURL, links := range output.ExcludedMap {
-		for _, link := range links {
-			result.Links = append(result.Links, LinkStatus{
-				URL:       link.URL,
-				Status:    "excluded",
-				SourceURL: sourceURL,
-			})
-		}
+// writeReport renders result and writes it to opts.OutputFile. The format
+// implied by the output file's extension takes precedence over opts.Format;
+// a format with no registered formatter (e.g. "compact") is rendered as json.
+func writeReport(result *Result, opts Options, pageCount int) error {
+	format := opts.Format
+	if ext := formatFromExtension(opts.OutputFile); ext != "" {
+		format = ext
 	}
 
-	// If user requested output to file, copy it or reformat
-	if opts.OutputFile != "" {
-		switch strings.ToLower(opts.Format) {
-		case "json":
-			// Copy JSON as-is
-			if err := os.WriteFile(opts.OutputFile, outputBytes, 0644); err != nil {
-				return nil, fmt.Errorf("failed to write output file: %w", err)
-			}
-		case "markdown", "md":
-			// Format as markdown
-			md := formatMarkdown(output, len(pageURLs))
-			if err := os.WriteFile(opts.OutputFile, []byte(md), 0644); err != nil {
-				return nil, fmt.Errorf("failed to write output file: %w", err)
-			}
-		default:
-			// Compact format - just copy JSON for now
-			if err := os.WriteFile(opts.OutputFile, outputBytes, 0644); err != nil {
-				return nil, fmt.Errorf("failed to write output file: %w", err)
-			}
-		}
-		fmt.Printf("Report written to %s\n", opts.OutputFile)
+	formatter, ok := GetFormatter(format)
+	if !ok {
+		formatter, _ = GetFormatter("json")
 	}
 
-	return result, nil
-}
+	data, err := formatter.Format(result, pageCount)
+	if err != nil {
+		return fmt.Errorf("failed to format report: %w", err)
+	}
 
-func formatMarkdown(output LycheeOutput, pageCount int) string {
-	var sb strings.Builder
-	sb.WriteString("# Link Audit Report\n\n")
-	sb.WriteString("## Summary\n\n")
-	sb.WriteString(fmt.Sprintf("- **Pages checked**: %d\n", pageCount))
-	sb.WriteString(fmt.Sprintf("- **Total links**: %d\n", output.Total))
-	sb.WriteString(fmt.Sprintf("- **Passed**: %d\n", output.Successful))
-	sb.WriteString(fmt.Sprintf("- **Failed**: %d\n", output.Errors))
-	sb.WriteString(fmt.Sprintf("- **Excluded**: %d\n", output.Excludes))
-	sb.WriteString("\n")
-
-	if output.Errors == 0 {
-		sb.WriteString("All links are valid!\n")
-	} else {
-		sb.WriteString("See JSON output for failure details.\n")
+	if err := writeFile(opts.OutputFile, data); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
-	return sb.String()
+	fmt.Printf("Report written to %s\n", opts.OutputFile)
+	return nil
 }
 
 // IsLycheeInstalled checks if lychee is available in PATH
 func IsLycheeInstalled() bool {
-	_, err := exec.LookPath("lychee")
-	return err == nil
+	return isLycheeInstalled()
 }
@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCheckURLs_CachePreservesPageCoverage guards against keying the cache
+// by sitemap page URL instead of extracted link URL: a page that's also
+// linked to from elsewhere must still be crawled for its own outbound
+// links every run, even once that page's URL is "fresh" in the cache as a
+// link.
+func TestCheckURLs_CachePreservesPageCoverage(t *testing.T) {
+	var pageHits, sharedHits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page-and-link", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pageHits, 1)
+		w.Write([]byte(`<html><body><a href="/only-found-here">x</a></body></html>`))
+	})
+	mux.HandleFunc("/only-found-here", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sharedHits, 1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cache, err := OpenCache(filepath.Join(t.TempDir(), "cache.bolt"))
+	if err != nil {
+		t.Fatalf("OpenCache: %v", err)
+	}
+	defer cache.Close()
+
+	// First run: nothing cached yet. Only the extracted link
+	// /only-found-here is stored; /page-and-link is linked to from nowhere
+	// here, so its own URL isn't cached yet.
+	pageURL := srv.URL + "/page-and-link"
+	if _, err := CheckURLs([]string{pageURL}, Options{Concurrency: 2, Timeout: 5, Engine: "native", Cache: cache, CacheTTL: time.Hour}); err != nil {
+		t.Fatalf("first CheckURLs: %v", err)
+	}
+	if atomic.LoadInt32(&pageHits) != 1 {
+		t.Fatalf("expected the page to be fetched once, got %d", pageHits)
+	}
+
+	// Manually seed a cache entry for the page's own URL, simulating it
+	// having been discovered as a link elsewhere in a prior run.
+	if err := cache.Store(&Result{Links: []LinkStatus{{URL: pageURL, Status: "ok", Code: 200}}}); err != nil {
+		t.Fatalf("seed Store: %v", err)
+	}
+
+	// Second run: pageURL is now "fresh" in the cache as a link, but it
+	// must still be crawled as a sitemap page — the cache must never
+	// filter CheckURLs's own pageURLs input.
+	if _, err := CheckURLs([]string{pageURL}, Options{Concurrency: 2, Timeout: 5, Engine: "native", Cache: cache, CacheTTL: time.Hour}); err != nil {
+		t.Fatalf("second CheckURLs: %v", err)
+	}
+	if atomic.LoadInt32(&pageHits) != 2 {
+		t.Fatalf("expected the page to still be fetched on the second run despite being cache-fresh as a link, got %d hits", pageHits)
+	}
+}
+
+// TestCheckURLs_CacheHitsSkipReprobingLinks is the positive case: once a
+// link has been checked, a second run against the same page must reuse the
+// cached result instead of re-probing that link.
+func TestCheckURLs_CacheHitsSkipReprobingLinks(t *testing.T) {
+	var linkHits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><a href="/target">x</a></body></html>`))
+	})
+	mux.HandleFunc("/target", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&linkHits, 1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cache, err := OpenCache(filepath.Join(t.TempDir(), "cache.bolt"))
+	if err != nil {
+		t.Fatalf("OpenCache: %v", err)
+	}
+	defer cache.Close()
+
+	opts := Options{Concurrency: 2, Timeout: 5, Engine: "native", Cache: cache, CacheTTL: time.Hour}
+	pageURL := srv.URL + "/page"
+
+	if _, err := CheckURLs([]string{pageURL}, opts); err != nil {
+		t.Fatalf("first CheckURLs: %v", err)
+	}
+	if atomic.LoadInt32(&linkHits) != 1 {
+		t.Fatalf("expected /target to be probed once, got %d", linkHits)
+	}
+
+	result, err := CheckURLs([]string{pageURL}, opts)
+	if err != nil {
+		t.Fatalf("second CheckURLs: %v", err)
+	}
+	if atomic.LoadInt32(&linkHits) != 1 {
+		t.Fatalf("expected /target to be served from cache on the second run, got %d probes", linkHits)
+	}
+	if result.PassedCount != 1 {
+		t.Errorf("expected the cache-reused link to still count toward PassedCount, got %d", result.PassedCount)
+	}
+}
@@ -0,0 +1,24 @@
+package checker
+
+import "testing"
+
+func TestValidateEngine(t *testing.T) {
+	cases := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"", false},
+		{"lychee", false},
+		{"native", false},
+		{"NATIVE", false},
+		{"natve", true},
+		{"bogus", true},
+	}
+
+	for _, c := range cases {
+		err := ValidateEngine(c.name)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ValidateEngine(%q) error = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Formatter renders a Result as a byte slice in a particular report format.
+type Formatter interface {
+	Format(result *Result, pageCount int) ([]byte, error)
+}
+
+// FormatterFunc adapts a plain function to the Formatter interface.
+type FormatterFunc func(result *Result, pageCount int) ([]byte, error)
+
+// Format calls f.
+func (f FormatterFunc) Format(result *Result, pageCount int) ([]byte, error) {
+	return f(result, pageCount)
+}
+
+var formatters = map[string]Formatter{
+	"json":     FormatterFunc(formatJSON),
+	"markdown": FormatterFunc(formatMarkdown),
+	"md":       FormatterFunc(formatMarkdown),
+	"sarif":    FormatterFunc(formatSARIF),
+	"junit":    FormatterFunc(formatJUnit),
+}
+
+// formatExtensions maps an output file extension to the format name that
+// should render it, so `--output foo.sarif` works without an explicit
+// `--format sarif`.
+var formatExtensions = map[string]string{
+	".sarif": "sarif",
+	".xml":   "junit",
+	".json":  "json",
+	".md":    "markdown",
+}
+
+// RegisterFormatter adds or replaces a named Formatter.
+func RegisterFormatter(name string, f Formatter) {
+	formatters[strings.ToLower(name)] = f
+}
+
+// GetFormatter looks up a Formatter by name.
+func GetFormatter(name string) (Formatter, bool) {
+	f, ok := formatters[strings.ToLower(name)]
+	return f, ok
+}
+
+// formatFromExtension returns the format implied by path's extension, or ""
+// if the extension isn't recognized.
+func formatFromExtension(path string) string {
+	return formatExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// formatJSON renders result as linkaudit's own Result schema (PassedCount,
+// FailedCount, ExcludedCount, Links), the same shape CheckURLs returns in
+// process. This is linkaudit's report format, not a passthrough of the
+// underlying engine's native output — lychee's own JSON schema differs and
+// is not reproduced here.
+func formatJSON(result *Result, _ int) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+func formatMarkdown(result *Result, pageCount int) ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString("# Link Audit Report\n\n")
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString(fmt.Sprintf("- **Pages checked**: %d\n", pageCount))
+	sb.WriteString(fmt.Sprintf("- **Total links**: %d\n", len(result.Links)))
+	sb.WriteString(fmt.Sprintf("- **Passed**: %d\n", result.PassedCount))
+	sb.WriteString(fmt.Sprintf("- **Failed**: %d\n", result.FailedCount))
+	sb.WriteString(fmt.Sprintf("- **Excluded**: %d\n", result.ExcludedCount))
+	sb.WriteString("\n")
+
+	if result.FailedCount == 0 {
+		sb.WriteString("All links are valid!\n")
+	} else {
+		sb.WriteString("## Broken Links\n\n")
+		for _, link := range result.Links {
+			if link.Code >= 400 || link.Status == "error" || link.Status == "failed" {
+				sb.WriteString(fmt.Sprintf("- [%s](%s) (%s) referenced from %s\n", link.URL, link.URL, link.Status, link.SourceURL))
+			}
+		}
+	}
+
+	return []byte(sb.String()), nil
+}
+
+// --- SARIF ---
+//
+// SARIF (Static Analysis Results Interchange Format) lets GitHub code
+// scanning render broken-link findings inline on a PR diff.
+
+type sarifLog struct {
+	XMLName xml.Name   `json:"-"`
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool    `json:"tool"`
+	Results []sarifEntry `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifEntry struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func formatSARIF(result *Result, _ int) ([]byte, error) {
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{Driver: sarifDriver{
+					Name:  "linkaudit",
+					Rules: []sarifRule{{ID: "BrokenLink"}},
+				}},
+			},
+		},
+	}
+
+	for _, link := range result.Links {
+		if link.Code < 400 && link.Status != "error" && link.Status != "failed" {
+			continue
+		}
+		log.Runs[0].Results = append(log.Runs[0].Results, sarifEntry{
+			RuleID:  "BrokenLink",
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("broken link %s (%s)", link.URL, link.Status)},
+			Locations: []sarifLocation{
+				{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: link.SourceURL}}},
+			},
+		})
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// --- JUnit ---
+//
+// JUnit XML lets Jenkins/GitLab/CircleCI display the audit as a test report:
+// one <testcase> per source page, with <failure> elements for its broken
+// links.
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name     string         `xml:"name,attr"`
+	Failures []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func formatJUnit(result *Result, _ int) ([]byte, error) {
+	bySource := make(map[string][]LinkStatus)
+	var sourceOrder []string
+	for _, link := range result.Links {
+		if _, ok := bySource[link.SourceURL]; !ok {
+			sourceOrder = append(sourceOrder, link.SourceURL)
+		}
+		bySource[link.SourceURL] = append(bySource[link.SourceURL], link)
+	}
+
+	suite := junitTestSuite{Name: "linkaudit"}
+	for _, source := range sourceOrder {
+		tc := junitTestCase{Name: source}
+		for _, link := range bySource[source] {
+			if link.Code < 400 && link.Status != "error" && link.Status != "failed" {
+				continue
+			}
+			tc.Failures = append(tc.Failures, junitFailure{
+				Message: fmt.Sprintf("broken link: %s", link.URL),
+				Text:    fmt.Sprintf("%s returned %s (code %d)", link.URL, link.Status, link.Code),
+			})
+		}
+		suite.Tests++
+		suite.Failures += len(tc.Failures)
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func writeFile(path string, data []byte) error {
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,120 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func sampleResult() *Result {
+	return &Result{
+		PassedCount: 1,
+		FailedCount: 1,
+		Links: []LinkStatus{
+			{URL: "https://example.com/ok", Status: "ok", Code: 200, SourceURL: "https://example.com/page1"},
+			{URL: "https://example.com/broken", Status: "failed", Code: 404, SourceURL: "https://example.com/page1"},
+		},
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := formatJSON(sampleResult(), 1)
+	if err != nil {
+		t.Fatalf("formatJSON: %v", err)
+	}
+
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("output isn't valid Result JSON: %v", err)
+	}
+	if decoded.FailedCount != 1 {
+		t.Errorf("expected FailedCount 1, got %d", decoded.FailedCount)
+	}
+}
+
+func TestFormatSARIF(t *testing.T) {
+	data, err := formatSARIF(sampleResult(), 1)
+	if err != nil {
+		t.Fatalf("formatSARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("output isn't valid SARIF JSON: %v", err)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(log.Runs))
+	}
+	if len(log.Runs[0].Results) != 1 {
+		t.Fatalf("expected exactly the broken link as a SARIF result, got %d", len(log.Runs[0].Results))
+	}
+	result := log.Runs[0].Results[0]
+	if result.RuleID != "BrokenLink" || result.Level != "error" {
+		t.Errorf("unexpected rule/level: %+v", result)
+	}
+	if result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "https://example.com/page1" {
+		t.Errorf("expected source page as artifact location, got %+v", result.Locations[0])
+	}
+}
+
+func TestFormatJUnit(t *testing.T) {
+	data, err := formatJUnit(sampleResult(), 1)
+	if err != nil {
+		t.Fatalf("formatJUnit: %v", err)
+	}
+
+	var suite junitTestSuite
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("output isn't valid JUnit XML: %v", err)
+	}
+	if suite.Tests != 1 {
+		t.Fatalf("expected 1 testcase (one source page), got %d", suite.Tests)
+	}
+	if suite.Failures != 1 {
+		t.Fatalf("expected 1 failure, got %d", suite.Failures)
+	}
+	if !strings.Contains(suite.TestCases[0].Failures[0].Message, "broken") {
+		t.Errorf("expected failure message to mention the broken link, got %q", suite.TestCases[0].Failures[0].Message)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	data, err := formatMarkdown(sampleResult(), 1)
+	if err != nil {
+		t.Fatalf("formatMarkdown: %v", err)
+	}
+	out := string(data)
+	if !strings.Contains(out, "Broken Links") {
+		t.Errorf("expected markdown to call out broken links, got: %s", out)
+	}
+	if !strings.Contains(out, "https://example.com/broken") {
+		t.Errorf("expected markdown to list the broken link, got: %s", out)
+	}
+}
+
+func TestFormatFromExtension(t *testing.T) {
+	cases := map[string]string{
+		"report.sarif": "sarif",
+		"report.xml":   "junit",
+		"report.json":  "json",
+		"report.md":    "markdown",
+		"report.txt":   "",
+	}
+	for path, want := range cases {
+		if got := formatFromExtension(path); got != want {
+			t.Errorf("formatFromExtension(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestGetFormatter_Registry(t *testing.T) {
+	for _, name := range []string{"json", "markdown", "sarif", "junit"} {
+		if _, ok := GetFormatter(name); !ok {
+			t.Errorf("expected a registered formatter for %q", name)
+		}
+	}
+	if _, ok := GetFormatter("does-not-exist"); ok {
+		t.Error("expected no formatter for an unknown name")
+	}
+}
@@ -0,0 +1,161 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// LycheeEngine checks links by shelling out to the lychee binary and
+// parsing its JSON output.
+type LycheeEngine struct{}
+
+// LycheeLink represents a single link in lychee output
+type LycheeLink struct {
+	URL    string `json:"url"`
+	Status struct {
+		Text string `json:"text"`
+		Code int    `json:"code"`
+	} `json:"status"`
+}
+
+// LycheeOutput represents the JSON output from lychee
+type LycheeOutput struct {
+	Total       int                     `json:"total"`
+	Successful  int                     `json:"successful"`
+	Errors      int                     `json:"errors"`
+	Excludes    int                     `json:"excludes"`
+	SuccessMap  map[string][]LycheeLink `json:"success_map"`
+	ErrorMap    map[string][]LycheeLink `json:"error_map"`
+	ExcludedMap map[string][]LycheeLink `json:"excluded_map"`
+}
+
+// Check runs lychee to check all links on the given page URLs. lychee
+// crawls pages and checks their links in one opaque subprocess call, so
+// unlike NativeEngine it can't skip probing links the cache already has
+// fresh — but it still records what it found, keyed per link, so a later
+// --engine native run can benefit.
+func (e *LycheeEngine) Check(pageURLs []string, opts Options) (*Result, error) {
+	if len(pageURLs) == 0 {
+		return &Result{}, nil
+	}
+
+	// Create temp directory for lychee files
+	tempDir, err := os.MkdirTemp("", "linkaudit-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Write URLs to temp file for lychee to read
+	urlsFile := filepath.Join(tempDir, "urls.txt")
+	if err := os.WriteFile(urlsFile, []byte(strings.Join(pageURLs, "\n")), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write urls file: %w", err)
+	}
+
+	// For JSON output or when we need to parse results, use JSON internally
+	jsonOutputFile := filepath.Join(tempDir, "output.json")
+
+	// Build lychee command
+	args := []string{
+		"--max-concurrency", fmt.Sprintf("%d", opts.Concurrency),
+		"--timeout", fmt.Sprintf("%d", opts.Timeout),
+		"--files-from", urlsFile,
+	}
+
+	// Always output JSON so we can parse results
+	args = append(args, "--format", "json", "--output", jsonOutputFile)
+
+	if opts.NoProgress {
+		args = append(args, "--no-progress")
+	}
+
+	if opts.ConfigFile != "" {
+		args = append(args, "--config", opts.ConfigFile)
+	}
+
+	for _, exclude := range opts.Excludes {
+		args = append(args, "--exclude", exclude)
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Running: lychee %s\n", strings.Join(args, " "))
+	}
+
+	// Run lychee
+	cmd := exec.Command("lychee", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Run and capture exit code (lychee exits 2 on broken links, which is not an error)
+	_ = cmd.Run()
+
+	// Parse JSON output
+	result := &Result{}
+
+	outputBytes, err := os.ReadFile(jsonOutputFile)
+	if err != nil {
+		// If no output, assume success
+		result.PassedCount = len(pageURLs)
+		return result, nil
+	}
+
+	var output LycheeOutput
+	if err := json.Unmarshal(outputBytes, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse lychee output: %w", err)
+	}
+
+	result.PassedCount = output.Successful
+	result.FailedCount = output.Errors
+	result.ExcludedCount = output.Excludes
+
+	// Collect all links with their status and source
+	for sourceURL, links := range output.SuccessMap {
+		for _, link := range links {
+			result.Links = append(result.Links, LinkStatus{
+				URL:       link.URL,
+				Status:    link.Status.Text,
+				Code:      link.Status.Code,
+				SourceURL: sourceURL,
+			})
+		}
+	}
+
+	for sourceURL, links := range output.ErrorMap {
+		for _, link := range links {
+			result.Links = append(result.Links, LinkStatus{
+				URL:       link.URL,
+				Status:    link.Status.Text,
+				Code:      link.Status.Code,
+				SourceURL: sourceURL,
+			})
+		}
+	}
+
+	for sourceURL, links := range output.ExcludedMap {
+		for _, link := range links {
+			result.Links = append(result.Links, LinkStatus{
+				URL:       link.URL,
+				Status:    "excluded",
+				SourceURL: sourceURL,
+			})
+		}
+	}
+
+	if opts.Cache != nil {
+		if err := opts.Cache.Store(result); err != nil {
+			return nil, fmt.Errorf("failed to update cache: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// isLycheeInstalled checks if lychee is available in PATH
+func isLycheeInstalled() bool {
+	_, err := exec.LookPath("lychee")
+	return err == nil
+}
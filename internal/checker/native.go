@@ -0,0 +1,384 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/time/rate"
+)
+
+// NativeEngine checks links in-process: it fetches every page, extracts
+// link-bearing attributes, and probes each unique link itself, without
+// depending on the lychee binary.
+type NativeEngine struct{}
+
+const (
+	nativeMaxRetries     = 3
+	nativeRetryBaseDelay = 500 * time.Millisecond
+	nativeRatePerHost    = 5 // requests/sec per host
+)
+
+// Check fetches each page in pageURLs, extracts its links, and probes every
+// unique link with a bounded worker pool.
+func (e *NativeEngine) Check(pageURLs []string, opts Options) (*Result, error) {
+	if len(pageURLs) == 0 {
+		return &Result{}, nil
+	}
+
+	excludes, err := compileExcludes(opts.Excludes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	limiter := &hostLimiter{limiters: make(map[string]*rate.Limiter)}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 20
+	}
+
+	links, excludedCount := extractPageLinks(client, pageURLs, excludes, concurrency)
+
+	// Group source pages by unique link so each link is probed exactly
+	// once, regardless of how many pages reference it.
+	sources := make(map[string][]string)
+	var uniqueLinks []string
+	for _, ref := range links {
+		if _, ok := sources[ref.link]; !ok {
+			uniqueLinks = append(uniqueLinks, ref.link)
+		}
+		sources[ref.link] = append(sources[ref.link], ref.sourceURL)
+	}
+
+	result := &Result{ExcludedCount: excludedCount}
+
+	toProbe := uniqueLinks
+	if opts.Cache != nil {
+		fresh, stale := opts.Cache.Split(uniqueLinks, linkLastMod(sources, opts.LastMod), opts.CacheTTL, opts.OnlyChanged)
+		toProbe = stale
+		appendByCurrentSources(result, fresh.Links, sources)
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var probed []LinkStatus
+
+	for _, link := range toProbe {
+		link := link
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			status, code, etag, lastModified := probeLink(client, limiter, link)
+
+			mu.Lock()
+			probed = append(probed, LinkStatus{URL: link, Status: status, Code: code, ETag: etag, LastModified: lastModified})
+			appendByCurrentSources(result, []LinkStatus{{URL: link, Status: status, Code: code, ETag: etag, LastModified: lastModified}}, sources)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	if opts.Cache != nil && len(probed) > 0 {
+		if err := opts.Cache.Store(&Result{Links: probed}); err != nil {
+			return nil, fmt.Errorf("failed to update cache: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// linkLastMod returns, for each link, the most recent sitemap <lastmod>
+// among the pages currently linking to it — so OnlyChanged re-checks a
+// link as soon as any of its current sources has changed, not just the
+// one it happened to be cached under.
+func linkLastMod(sources map[string][]string, pageLastMod map[string]time.Time) map[string]time.Time {
+	out := make(map[string]time.Time, len(sources))
+	for link, pages := range sources {
+		var latest time.Time
+		for _, p := range pages {
+			if t, ok := pageLastMod[p]; ok && t.After(latest) {
+				latest = t
+			}
+		}
+		out[link] = latest
+	}
+	return out
+}
+
+// appendByCurrentSources expands each link in links into one LinkStatus per
+// page in sources that currently references it, tallying result's counts
+// accordingly. This is used both for links reused from the cache (whose
+// single stored SourceURL may be stale) and for freshly probed ones.
+func appendByCurrentSources(result *Result, links []LinkStatus, sources map[string][]string) {
+	for _, l := range links {
+		for _, sourceURL := range sources[l.URL] {
+			result.Links = append(result.Links, LinkStatus{
+				URL: l.URL, Status: l.Status, Code: l.Code, SourceURL: sourceURL,
+				ETag: l.ETag, LastModified: l.LastModified,
+			})
+			switch classifyLink(l.Status, l.Code) {
+			case "failed":
+				result.FailedCount++
+			case "excluded":
+				result.ExcludedCount++
+			default:
+				result.PassedCount++
+			}
+		}
+	}
+}
+
+type pageLink struct {
+	link      string
+	sourceURL string
+}
+
+// extractPageLinks fetches each page URL and extracts every <a href>,
+// <img src>, <link href>, and <script src>, resolved against the page's own
+// URL. Links matching an exclude pattern are counted but not returned. Pages
+// are fetched concurrently, bounded by concurrency, since page-fetch time
+// otherwise dominates on large sitemaps.
+func extractPageLinks(client *http.Client, pageURLs []string, excludes []*regexp.Regexp, concurrency int) ([]pageLink, int) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	var links []pageLink
+	excludedCount := 0
+
+	for _, pageURL := range pageURLs {
+		pageURL := pageURL
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			base, err := url.Parse(pageURL)
+			if err != nil {
+				return
+			}
+
+			resp, err := client.Get(pageURL)
+			if err != nil {
+				return
+			}
+
+			hrefs := extractHrefs(resp.Body)
+			resp.Body.Close()
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, href := range hrefs {
+				resolved, err := base.Parse(href)
+				if err != nil {
+					continue
+				}
+				if !resolved.IsAbs() {
+					continue
+				}
+
+				if matchesAny(excludes, resolved.String()) {
+					excludedCount++
+					continue
+				}
+
+				links = append(links, pageLink{link: resolved.String(), sourceURL: pageURL})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return links, excludedCount
+}
+
+// extractHrefs walks an HTML document and collects every attribute value
+// that can point at another resource: <a href>, <img src>, <link href>, and
+// <script src>.
+func extractHrefs(r io.Reader) []string {
+	var hrefs []string
+	tokenizer := html.NewTokenizer(r)
+
+	wantedAttr := map[string]string{
+		"a":      "href",
+		"img":    "src",
+		"link":   "href",
+		"script": "src",
+	}
+
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			return hrefs
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attrName, ok := wantedAttr[strings.ToLower(token.Data)]
+			if !ok {
+				continue
+			}
+			for _, a := range token.Attr {
+				if strings.EqualFold(a.Key, attrName) && a.Val != "" {
+					hrefs = append(hrefs, a.Val)
+				}
+			}
+		}
+	}
+}
+
+func compileExcludes(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostLimiter rate-limits outgoing requests per destination host so a
+// native-engine run doesn't hammer any single site.
+type hostLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func (h *hostLimiter) wait(ctx context.Context, host string) {
+	h.mu.Lock()
+	limiter, ok := h.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(nativeRatePerHost), nativeRatePerHost)
+		h.limiters[host] = limiter
+	}
+	h.mu.Unlock()
+
+	_ = limiter.Wait(ctx)
+}
+
+// probeLink checks a single link with a HEAD request, falling back to GET
+// when HEAD isn't supported, retrying with exponential backoff on 5xx/429
+// responses (honoring Retry-After when present). It returns the response's
+// ETag/Last-Modified headers alongside status/code so callers can cache
+// them for conditional re-checks.
+func probeLink(client *http.Client, limiter *hostLimiter, link string) (status string, code int, etag string, lastModified string) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "error", 0, "", ""
+	}
+
+	var last probeResult
+	for attempt := 0; attempt <= nativeMaxRetries; attempt++ {
+		limiter.wait(context.Background(), parsed.Host)
+
+		probe, err := doProbe(client, link)
+		last = probe
+		if err != nil {
+			return "error", 0, "", ""
+		}
+
+		if probe.code < 500 && probe.code != http.StatusTooManyRequests {
+			if probe.code >= 400 {
+				return "failed", probe.code, probe.etag, probe.lastModified
+			}
+			return "ok", probe.code, probe.etag, probe.lastModified
+		}
+
+		if attempt == nativeMaxRetries {
+			break
+		}
+
+		delay := probe.retryAfter
+		if delay <= 0 {
+			delay = nativeRetryBaseDelay * time.Duration(math.Pow(2, float64(attempt)))
+		}
+		time.Sleep(delay)
+	}
+
+	return "failed", last.code, last.etag, last.lastModified
+}
+
+// probeResult is the outcome of a single HEAD/GET attempt in doProbe.
+type probeResult struct {
+	code         int
+	retryAfter   time.Duration
+	etag         string
+	lastModified string
+}
+
+// doProbe issues a HEAD request, falling back to GET if the server rejects
+// HEAD (405) or returns no useful status. It returns the status code, any
+// Retry-After duration the server requested, and the ETag/Last-Modified
+// response headers.
+func doProbe(client *http.Client, link string) (probeResult, error) {
+	resp, err := client.Head(link)
+	if err == nil && resp.StatusCode != http.StatusMethodNotAllowed {
+		defer resp.Body.Close()
+		return responseToProbeResult(resp), nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	resp, err = client.Get(link)
+	if err != nil {
+		return probeResult{}, err
+	}
+	defer resp.Body.Close()
+	return responseToProbeResult(resp), nil
+}
+
+func responseToProbeResult(resp *http.Response) probeResult {
+	return probeResult{
+		code:         resp.StatusCode,
+		retryAfter:   retryAfterDuration(resp),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+func retryAfterDuration(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
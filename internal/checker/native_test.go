@@ -0,0 +1,222 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+// newTestHostLimiter returns a fresh hostLimiter for a single test. The
+// default per-host burst (nativeRatePerHost) covers the handful of requests
+// these tests make, so it doesn't slow them down.
+func newTestHostLimiter() *hostLimiter {
+	return &hostLimiter{limiters: make(map[string]*rate.Limiter)}
+}
+
+func TestExtractHrefs(t *testing.T) {
+	body := `<html><body>
+<a href="/a">a</a>
+<img src="/b.png">
+<link href="/c.css">
+<script src="/d.js"></script>
+<a href="">empty</a>
+</body></html>`
+
+	hrefs := extractHrefs(strings.NewReader(body))
+	want := []string{"/a", "/b.png", "/c.css", "/d.js"}
+	if len(hrefs) != len(want) {
+		t.Fatalf("extractHrefs = %v, want %v", hrefs, want)
+	}
+	for i, w := range want {
+		if hrefs[i] != w {
+			t.Errorf("extractHrefs[%d] = %q, want %q", i, hrefs[i], w)
+		}
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	excludes, err := compileExcludes([]string{`\.pdf$`})
+	if err != nil {
+		t.Fatalf("compileExcludes: %v", err)
+	}
+	if !matchesAny(excludes, "https://example.com/file.pdf") {
+		t.Error("expected .pdf URL to match exclude pattern")
+	}
+	if matchesAny(excludes, "https://example.com/file.html") {
+		t.Error("expected .html URL not to match exclude pattern")
+	}
+}
+
+func TestDoProbe_HeadThenGetFallback(t *testing.T) {
+	var headCalls, getCalls int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/head-ok", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			atomic.AddInt32(&headCalls, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/head-unsupported", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			atomic.AddInt32(&headCalls, 1)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		atomic.AddInt32(&getCalls, 1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &http.Client{}
+
+	probe, err := doProbe(client, srv.URL+"/head-ok")
+	if err != nil || probe.code != http.StatusOK {
+		t.Fatalf("doProbe(head-ok) = %+v, %v", probe, err)
+	}
+	if atomic.LoadInt32(&getCalls) != 0 {
+		t.Fatalf("expected no GET fallback when HEAD succeeds")
+	}
+
+	probe, err = doProbe(client, srv.URL+"/head-unsupported")
+	if err != nil || probe.code != http.StatusOK {
+		t.Fatalf("doProbe(head-unsupported) = %+v, %v", probe, err)
+	}
+	if atomic.LoadInt32(&getCalls) != 1 {
+		t.Fatalf("expected exactly one GET fallback after HEAD 405, got %d", getCalls)
+	}
+}
+
+func TestDoProbe_CapturesETagAndLastModified(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tagged", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &http.Client{}
+	probe, err := doProbe(client, srv.URL+"/tagged")
+	if err != nil {
+		t.Fatalf("doProbe: %v", err)
+	}
+	if probe.etag != `"abc123"` {
+		t.Errorf("expected ETag %q, got %q", `"abc123"`, probe.etag)
+	}
+	if probe.lastModified != "Wed, 21 Oct 2015 07:28:00 GMT" {
+		t.Errorf("expected Last-Modified to be captured, got %q", probe.lastModified)
+	}
+}
+
+func TestProbeLink_RetriesOn5xxThenSucceeds(t *testing.T) {
+	var attempts int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &http.Client{}
+
+	status, code, _, _ := probeLink(client, newTestHostLimiter(), srv.URL+"/flaky")
+	if status != "ok" || code != http.StatusOK {
+		t.Fatalf("probeLink = (%q, %d), want (ok, 200)", status, code)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Fatalf("expected 2 attempts (1 failure + 1 success), got %d", attempts)
+	}
+}
+
+func TestProbeLink_FailsAfterExhaustingRetries(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/always-503", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &http.Client{}
+	status, code, _, _ := probeLink(client, newTestHostLimiter(), srv.URL+"/always-503")
+	if status != "failed" || code != http.StatusServiceUnavailable {
+		t.Fatalf("probeLink = (%q, %d), want (failed, 503)", status, code)
+	}
+}
+
+func TestNativeEngine_Check(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><a href="/good">g</a><a href="/missing">m</a></body></html>`))
+	})
+	mux.HandleFunc("/good", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) })
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	engine := &NativeEngine{}
+	result, err := engine.Check([]string{srv.URL + "/page"}, Options{Concurrency: 4, Timeout: 5})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if result.PassedCount != 1 || result.FailedCount != 1 {
+		t.Fatalf("expected 1 passed and 1 failed, got passed=%d failed=%d (%v)", result.PassedCount, result.FailedCount, result.Links)
+	}
+}
+
+func TestNativeEngine_DedupesSharedLinks(t *testing.T) {
+	var missingHits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><a href="/shared">s</a></body></html>`))
+	})
+	mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><a href="/shared">s</a></body></html>`))
+	})
+	mux.HandleFunc("/shared", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&missingHits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	engine := &NativeEngine{}
+	result, err := engine.Check([]string{srv.URL + "/page1", srv.URL + "/page2"}, Options{Concurrency: 4, Timeout: 5})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if atomic.LoadInt32(&missingHits) != 1 {
+		t.Fatalf("expected the shared link to be probed exactly once, got %d probes", missingHits)
+	}
+	if len(result.Links) != 2 {
+		t.Fatalf("expected one LinkStatus per source page (2 total), got %d: %v", len(result.Links), result.Links)
+	}
+	for _, link := range result.Links {
+		if link.Status == "" {
+			t.Errorf("expected every LinkStatus to have a resolved status, got empty for %s", link.SourceURL)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package metrics exposes Prometheus counters and histograms tracking the
+// pages and links linkaudit has checked, served over HTTP via Serve so a
+// scraper can collect them.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+var (
+	// PagesTotal counts every page URL submitted to the checker.
+	PagesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "linkaudit_pages_total",
+		Help: "Total number of page URLs checked.",
+	})
+
+	// LinksPassedTotal counts links that resolved successfully.
+	LinksPassedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "linkaudit_links_passed_total",
+		Help: "Total number of links that passed the check.",
+	})
+
+	// LinksFailedTotal counts broken links, labeled by HTTP status code.
+	LinksFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "linkaudit_links_failed_total",
+		Help: "Total number of links that failed the check, by status code.",
+	}, []string{"status_code"})
+
+	// SitemapFetchDuration observes how long each sitemap fetch took.
+	SitemapFetchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "linkaudit_sitemap_fetch_duration_seconds",
+		Help:    "Duration of individual sitemap fetches, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// PerSitemapFailed counts sitemaps that failed to fetch or parse,
+	// labeled by the sitemap URL.
+	PerSitemapFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "linkaudit_per_sitemap_failed",
+		Help: "Total number of sitemap fetch/parse failures, by sitemap URL.",
+	}, []string{"sitemap"})
+)
+
+// Serve starts an HTTP server on addr exposing the registered metrics at
+// /metrics. It blocks until the server stops or returns an error.
+//
+// linkaudit is a one-shot CLI: the process exits as soon as the check
+// finishes, so Serve only makes sense for ad-hoc local inspection (e.g.
+// leaving a run open in a terminal). A scheduled job that wants long-term
+// trend data can't rely on a pull-based scrape landing inside that window
+// and should use Push instead.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(addr, mux)
+}
+
+// Push sends the current metrics to a Prometheus Pushgateway at gatewayURL
+// under the given job name, so a scheduled linkaudit run can report
+// long-term trend data despite exiting before any scrape could occur.
+func Push(gatewayURL, job string) error {
+	return push.New(gatewayURL, job).Gatherer(prometheus.DefaultGatherer).Push()
+}
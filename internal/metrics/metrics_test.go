@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServe_ExposesRegisteredMetrics(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve a port: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	go func() {
+		_ = Serve(addr)
+	}()
+
+	PagesTotal.Add(3)
+
+	var body string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err == nil {
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			body = string(data)
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !strings.Contains(body, "linkaudit_pages_total") {
+		t.Fatalf("expected /metrics to expose linkaudit_pages_total, got: %s", body)
+	}
+}
+
+func TestPush_SendsMetricsToGateway(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "linkaudit_pages_total") {
+			t.Errorf("expected pushed body to include linkaudit_pages_total, got: %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if err := Push(srv.URL, "linkaudit_test"); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if gotMethod != http.MethodPut {
+		t.Errorf("expected Push to PUT the gateway, got %s", gotMethod)
+	}
+}
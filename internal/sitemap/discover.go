@@ -0,0 +1,113 @@
+package sitemap
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// candidateSitemaps are tried in order when robots.txt declares none.
+var candidateSitemaps = []string{"/sitemap.xml", "/sitemap_index.xml"}
+
+// Discover takes a site root (e.g. "https://example.com") and locates its
+// sitemap(s): it fetches /robots.txt and follows any declared "Sitemap:"
+// directives, falling back to /sitemap.xml and /sitemap_index.xml if
+// robots.txt declares none or can't be fetched.
+func Discover(baseURL string) (*FetchResult, error) {
+	return NewFetcher(Options{}).Discover(baseURL)
+}
+
+// Discover is the Fetcher-bound equivalent of the package-level Discover,
+// reusing the Fetcher's client and concurrency settings.
+func (f *Fetcher) Discover(baseURL string) (*FetchResult, error) {
+	root, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	result := newFetchResult()
+
+	sitemapURLs, err := f.robotsSitemaps(root)
+	if err == nil && len(sitemapURLs) > 0 {
+		for _, smURL := range sitemapURLs {
+			sub, ferr := f.FetchGrouped(smURL)
+			if sub != nil {
+				// sub already carries its own per-sitemap errors.
+				f.markSource(sub, "robots.txt")
+				result.merge(sub)
+			} else if ferr != nil {
+				result.addError(smURL, ferr)
+			}
+		}
+		if len(result.Sitemaps) > 0 {
+			result.AllURLs = dedupe(result.AllURLs)
+			return result, nil
+		}
+	}
+
+	for _, path := range candidateSitemaps {
+		candidate := root.ResolveReference(&url.URL{Path: path}).String()
+		sub, ferr := f.FetchGrouped(candidate)
+		if ferr != nil {
+			result.addError(candidate, ferr)
+			continue
+		}
+		f.markSource(sub, strings.TrimPrefix(path, "/"))
+		result.merge(sub)
+		result.AllURLs = dedupe(result.AllURLs)
+		return result, nil
+	}
+
+	return result, fmt.Errorf("could not discover a sitemap for %s: no robots.txt Sitemap: directive and no sitemap.xml/sitemap_index.xml found", baseURL)
+}
+
+// markSource tags every sitemap URL in sub with source, unless it was
+// already tagged (e.g. by a nested Discover call).
+func (f *Fetcher) markSource(sub *FetchResult, source string) {
+	for smURL := range sub.Sitemaps {
+		if _, ok := sub.Sources[smURL]; !ok {
+			sub.setSource(smURL, source)
+		}
+	}
+}
+
+// robotsSitemaps fetches robots.txt and returns every URL named in a
+// "Sitemap:" directive, per the sitemaps.org robots.txt extension.
+func (f *Fetcher) robotsSitemaps(root *url.URL) ([]string, error) {
+	robotsURL := root.ResolveReference(&url.URL{Path: "/robots.txt"}).String()
+
+	req, err := http.NewRequest("GET", robotsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create robots.txt request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+	}
+
+	var sitemaps []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(strings.ToLower(line), "sitemap:") {
+			continue
+		}
+		value := strings.TrimSpace(line[len("sitemap:"):])
+		if value != "" {
+			sitemaps = append(sitemaps, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read robots.txt: %w", err)
+	}
+
+	return sitemaps, nil
+}
@@ -0,0 +1,79 @@
+package sitemap
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiscover_RobotsTxt(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "User-agent: *")
+		fmt.Fprintln(w, "Disallow: /admin")
+		fmt.Fprintln(w, "Sitemap: {{base}}/sitemap.xml")
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, urlSetXML("{{base}}/page"))
+	})
+	// A /sitemap_index.xml fallback handler that should never be hit since
+	// robots.txt already declared a sitemap.
+	mux.HandleFunc("/sitemap_index.xml", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("fallback sitemap_index.xml should not be fetched when robots.txt declares one")
+	})
+
+	srv := httptest.NewServer(templatingHandler(mux))
+	defer srv.Close()
+
+	result, err := NewFetcher(Options{}).Discover(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.AllURLs) != 1 {
+		t.Fatalf("expected 1 URL, got %v", result.AllURLs)
+	}
+	for smURL, source := range result.Sources {
+		if source != "robots.txt" {
+			t.Errorf("expected source robots.txt for %s, got %q", smURL, source)
+		}
+	}
+}
+
+func TestDiscover_FallbackToSitemapXML(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", http.NotFound)
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, urlSetXML("{{base}}/page"))
+	})
+
+	srv := httptest.NewServer(templatingHandler(mux))
+	defer srv.Close()
+
+	result, err := NewFetcher(Options{}).Discover(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.AllURLs) != 1 {
+		t.Fatalf("expected 1 URL from sitemap.xml fallback, got %v", result.AllURLs)
+	}
+	if got := result.Sources[srv.URL+"/sitemap.xml"]; got != "sitemap.xml" {
+		t.Errorf("expected source sitemap.xml, got %q", got)
+	}
+}
+
+func TestDiscover_NoneFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", http.NotFound)
+	mux.HandleFunc("/sitemap.xml", http.NotFound)
+	mux.HandleFunc("/sitemap_index.xml", http.NotFound)
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, err := NewFetcher(Options{}).Discover(srv.URL)
+	if err == nil {
+		t.Fatal("expected an error when no sitemap can be discovered")
+	}
+}
@@ -0,0 +1,159 @@
+package sitemap
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Devon-White/link_checker/internal/metrics"
+)
+
+// DefaultConcurrency is the number of sitemaps a Fetcher will fetch at once
+// when Options.Concurrency is left unset.
+const DefaultConcurrency = 5
+
+// Options configures a Fetcher.
+type Options struct {
+	// Concurrency bounds how many child sitemaps are fetched in parallel
+	// when descending a sitemap index.
+	Concurrency int
+	// Client is the HTTP client used for all requests. Defaults to a
+	// client with a 30s timeout.
+	Client *http.Client
+}
+
+// SitemapError records a failure to fetch or parse a single sitemap URL
+// encountered while walking a sitemap index. Fetcher collects these instead
+// of aborting the whole run so callers can report partial failures.
+type SitemapError struct {
+	SitemapURL string
+	Err        error
+}
+
+func (e *SitemapError) Error() string {
+	return fmt.Sprintf("%s: %v", e.SitemapURL, e.Err)
+}
+
+func (e *SitemapError) Unwrap() error {
+	return e.Err
+}
+
+// Fetcher fetches and parses sitemaps, descending sitemap indexes
+// concurrently and guarding against cycles.
+type Fetcher struct {
+	client      *http.Client
+	concurrency int
+}
+
+// NewFetcher builds a Fetcher from opts, applying defaults for any zero
+// values.
+func NewFetcher(opts Options) *Fetcher {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	return &Fetcher{client: client, concurrency: concurrency}
+}
+
+// FetchGrouped retrieves and parses a sitemap (or sitemap index, recursively),
+// returning URLs grouped by the sitemap that declared them.
+func (f *Fetcher) FetchGrouped(sitemapURL string) (*FetchResult, error) {
+	result := newFetchResult()
+	var visited sync.Map
+	f.fetchInto(sitemapURL, result, &visited)
+
+	result.AllURLs = dedupe(result.AllURLs)
+	if len(result.Sitemaps) == 0 && len(result.Errors) > 0 {
+		return result, fmt.Errorf("failed to fetch sitemap: %w", result.Errors[0])
+	}
+	return result, nil
+}
+
+// fetchInto fetches sitemapURL and merges it into result. If sitemapURL is a
+// sitemap index, its children are fetched concurrently (bounded by
+// f.concurrency) and recursively descended. Already-visited URLs are skipped
+// so a misconfigured or malicious index can't cause infinite recursion.
+func (f *Fetcher) fetchInto(sitemapURL string, result *FetchResult, visited *sync.Map) {
+	if _, loaded := visited.LoadOrStore(sitemapURL, struct{}{}); loaded {
+		return
+	}
+
+	start := time.Now()
+	urlSet, index, err := f.fetchOne(sitemapURL)
+	metrics.SitemapFetchDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.PerSitemapFailed.WithLabelValues(sitemapURL).Inc()
+		result.addError(sitemapURL, err)
+		return
+	}
+
+	if index != nil {
+		f.fetchChildren(index.Sitemaps, result, visited)
+		return
+	}
+
+	urls := make([]string, 0, len(urlSet.URLs))
+	for _, u := range urlSet.URLs {
+		urls = append(urls, u.Loc)
+		if t, ok := parseLastMod(u.LastMod); ok {
+			result.setLastMod(u.Loc, t)
+		}
+	}
+
+	result.addSitemap(sitemapURL, urls)
+}
+
+// fetchChildren fetches each child sitemap of an index through a worker pool
+// sized by f.concurrency.
+func (f *Fetcher) fetchChildren(children []Sitemap, result *FetchResult, visited *sync.Map) {
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+
+	for _, sm := range children {
+		sm := sm
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			f.fetchInto(sm.Loc, result, visited)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// fetchOne fetches a single sitemap URL and parses it as either a regular
+// urlset or a sitemap index.
+func (f *Fetcher) fetchOne(sitemapURL string) (*URLSet, *SitemapIndex, error) {
+	req, err := http.NewRequest("GET", sitemapURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/xml, text/xml")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch sitemap: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("sitemap returned status %d", resp.StatusCode)
+	}
+
+	body, err := readSitemapBody(resp, sitemapURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parseSitemapBody(body)
+}
@@ -0,0 +1,156 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func urlSetXML(loc string) string {
+	return fmt.Sprintf(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>%s</loc></url></urlset>`, loc)
+}
+
+func TestFetcher_RecursiveIndex(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>{{base}}/nested-index.xml</loc></sitemap>
+</sitemapindex>`)
+	})
+	mux.HandleFunc("/nested-index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>{{base}}/leaf1.xml</loc></sitemap>
+<sitemap><loc>{{base}}/leaf2.xml</loc></sitemap>
+</sitemapindex>`)
+	})
+	mux.HandleFunc("/leaf1.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, urlSetXML("{{base}}/page1"))
+	})
+	mux.HandleFunc("/leaf2.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, urlSetXML("{{base}}/page2"))
+	})
+
+	srv := httptest.NewServer(templatingHandler(mux))
+	defer srv.Close()
+
+	fetcher := NewFetcher(Options{Concurrency: 2})
+	result, err := fetcher.FetchGrouped(srv.URL + "/index.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Sitemaps) != 2 {
+		t.Fatalf("expected 2 leaf sitemaps, got %d: %v", len(result.Sitemaps), result.Sitemaps)
+	}
+	if len(result.AllURLs) != 2 {
+		t.Fatalf("expected 2 URLs, got %d: %v", len(result.AllURLs), result.AllURLs)
+	}
+}
+
+// templatingHandler replaces "{{base}}" in every response with the server's
+// own URL, since httptest.Server's address isn't known until it starts.
+func templatingHandler(next http.Handler) http.HandlerFunc {
+	var base atomic.Value
+	base.Store("")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if base.Load().(string) == "" {
+			base.Store("http://" + r.Host)
+		}
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+		body := bytes.ReplaceAll(rec.Body.Bytes(), []byte("{{base}}"), []byte(base.Load().(string)))
+		w.Write(body)
+	}
+}
+
+func TestFetcher_CycleDetection(t *testing.T) {
+	var hits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a.xml", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprint(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>{{base}}/b.xml</loc></sitemap>
+</sitemapindex>`)
+	})
+	mux.HandleFunc("/b.xml", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprint(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>{{base}}/a.xml</loc></sitemap>
+</sitemapindex>`)
+	})
+
+	srv := httptest.NewServer(templatingHandler(mux))
+	defer srv.Close()
+
+	fetcher := NewFetcher(Options{Concurrency: 2})
+	result, err := fetcher.FetchGrouped(srv.URL + "/a.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if atomic.LoadInt32(&hits) != 2 {
+		t.Fatalf("expected each sitemap in the cycle to be fetched exactly once, got %d fetches", hits)
+	}
+	if len(result.Sitemaps) != 0 {
+		t.Fatalf("expected no leaf urlsets in a pure index cycle, got %v", result.Sitemaps)
+	}
+}
+
+func TestFetcher_GzipSitemap(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(urlSetXML("https://example.com/page")))
+		gz.Close()
+		w.Write(buf.Bytes())
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	fetcher := NewFetcher(Options{})
+	result, err := fetcher.FetchGrouped(srv.URL + "/sitemap.xml.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.AllURLs) != 1 || result.AllURLs[0] != "https://example.com/page" {
+		t.Fatalf("expected one decompressed URL, got %v", result.AllURLs)
+	}
+}
+
+func TestFetcher_PerSitemapError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>{{base}}/good.xml</loc></sitemap>
+<sitemap><loc>{{base}}/missing.xml</loc></sitemap>
+</sitemapindex>`)
+	})
+	mux.HandleFunc("/good.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, urlSetXML("{{base}}/page"))
+	})
+	mux.HandleFunc("/missing.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	srv := httptest.NewServer(templatingHandler(mux))
+	defer srv.Close()
+
+	fetcher := NewFetcher(Options{})
+	result, err := fetcher.FetchGrouped(srv.URL + "/index.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Sitemaps) != 1 {
+		t.Fatalf("expected the good sitemap to still be fetched, got %v", result.Sitemaps)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected the missing sitemap to be recorded as an error, got %v", result.Errors)
+	}
+}
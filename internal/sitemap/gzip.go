@@ -0,0 +1,48 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// readSitemapBody reads resp.Body, transparently decompressing it when the
+// server sent gzip-encoded content, as detected by isGzipped.
+func readSitemapBody(resp *http.Response, sourceURL string) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sitemap body: %w", err)
+	}
+
+	if isGzipped(resp, sourceURL, body) {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip sitemap: %w", err)
+		}
+		defer gz.Close()
+
+		decompressed, err := io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip sitemap: %w", err)
+		}
+		return decompressed, nil
+	}
+
+	return body, nil
+}
+
+// isGzipped reports whether the response body is gzip-compressed, based on
+// the Content-Encoding header, a ".gz" URL suffix, or the gzip magic
+// bytes (some servers serve compressed sitemaps with neither set correctly).
+func isGzipped(resp *http.Response, sourceURL string, body []byte) bool {
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return true
+	}
+	if strings.HasSuffix(strings.ToLower(sourceURL), ".gz") {
+		return true
+	}
+	return len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b
+}
@@ -3,8 +3,7 @@ package sitemap
 import (
 	"encoding/xml"
 	"fmt"
-	"io"
-	"net/http"
+	"sync"
 	"time"
 )
 
@@ -16,7 +15,8 @@ type URLSet struct {
 
 // URL represents a single URL entry in the sitemap
 type URL struct {
-	Loc string `xml:"loc"`
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
 }
 
 // SitemapIndex represents a sitemap index file
@@ -36,150 +36,141 @@ type FetchResult struct {
 	Sitemaps map[string][]string
 	// AllURLs is a flat list of all URLs (for merged mode)
 	AllURLs []string
+	// Sources maps a sitemap URL to how it was discovered, e.g.
+	// "robots.txt", "sitemap.xml", "sitemap_index.xml", or "" when the
+	// caller supplied the sitemap URL directly.
+	Sources map[string]string
+	// LastMod maps a page URL to the <lastmod> value from the sitemap
+	// that declared it, when present and parseable.
+	LastMod map[string]time.Time
+	// Errors collects per-sitemap failures encountered while descending a
+	// sitemap index, instead of silently dropping them.
+	Errors []*SitemapError
+
+	// mu guards every field above. A sitemap index's children are fetched
+	// concurrently (see Fetcher.fetchChildren), and each child merges its
+	// findings into the shared result.
+	mu sync.Mutex
 }
 
-
-
-// Fetch retrieves and parses a sitemap, returning all page URLs (merged)
-func Fetch(sitemapURL string) ([]string, error) {
-	result, err := FetchGrouped(sitemapURL)
-	if err != nil {
-		return nil, err
+func newFetchResult() *FetchResult {
+	return &FetchResult{
+		Sitemaps: make(map[string][]string),
+		Sources:  make(map[string]string),
+		LastMod:  make(map[string]time.Time),
 	}
-	return result.AllURLs, nil
 }
 
-// FetchGrouped retrieves and parses a sitemap, returning URLs grouped by source sitemap
-func FetchGrouped(sitemapURL string) (*FetchResult, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+func (r *FetchResult) addSitemap(sitemapURL string, urls []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Sitemaps[sitemapURL] = urls
+	r.AllURLs = append(r.AllURLs, urls...)
+}
 
-	req, err := http.NewRequest("GET", sitemapURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+func (r *FetchResult) addError(sitemapURL string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errors = append(r.Errors, &SitemapError{SitemapURL: sitemapURL, Err: err})
+}
 
-	// Request XML explicitly to avoid getting HTML rendering
-	req.Header.Set("Accept", "application/xml, text/xml")
+func (r *FetchResult) setLastMod(pageURL string, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.LastMod[pageURL] = t
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sitemap: %w", err)
-	}
-	defer resp.Body.Close()
+func (r *FetchResult) setSource(sitemapURL, source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Sources[sitemapURL] = source
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("sitemap returned status %d", resp.StatusCode)
+// merge folds other into r, prefixing nothing — callers use this to combine
+// results from multiple discovery attempts (e.g. several robots.txt
+// Sitemap: directives).
+func (r *FetchResult) merge(other *FetchResult) {
+	other.mu.Lock()
+	sitemaps := make(map[string][]string, len(other.Sitemaps))
+	for k, v := range other.Sitemaps {
+		sitemaps[k] = v
+	}
+	sources := make(map[string]string, len(other.Sources))
+	for k, v := range other.Sources {
+		sources[k] = v
+	}
+	lastMod := make(map[string]time.Time, len(other.LastMod))
+	for k, v := range other.LastMod {
+		lastMod[k] = v
+	}
+	errs := append([]*SitemapError{}, other.Errors...)
+	other.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for smURL, urls := range sitemaps {
+		r.Sitemaps[smURL] = urls
+		r.AllURLs = append(r.AllURLs, urls...)
+	}
+	for smURL, source := range sources {
+		r.Sources[smURL] = source
+	}
+	for pageURL, t := range lastMod {
+		r.LastMod[pageURL] = t
 	}
+	r.Errors = append(r.Errors, errs...)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// Fetch retrieves and parses a sitemap, returning all page URLs (merged)
+func Fetch(sitemapURL string) ([]string, error) {
+	result, err := FetchGrouped(sitemapURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read sitemap body: %w", err)
+		return nil, err
 	}
+	return result.AllURLs, nil
+}
 
-	result := &FetchResult{
-		Sitemaps: make(map[string][]string),
-	}
+// FetchGrouped retrieves and parses a sitemap, returning URLs grouped by
+// source sitemap. It descends sitemap indexes recursively, fetching children
+// concurrently, and supports gzip-compressed sitemaps transparently.
+func FetchGrouped(sitemapURL string) (*FetchResult, error) {
+	return NewFetcher(Options{}).FetchGrouped(sitemapURL)
+}
 
-	// Try parsing as sitemap index first
+// parseSitemapBody parses body as either a sitemap index or a regular
+// urlset, returning whichever one matched.
+func parseSitemapBody(body []byte) (*URLSet, *SitemapIndex, error) {
 	var sitemapIndex SitemapIndex
 	if err := xml.Unmarshal(body, &sitemapIndex); err == nil && len(sitemapIndex.Sitemaps) > 0 {
-		return fetchSitemapIndexGrouped(client, sitemapIndex)
+		return nil, &sitemapIndex, nil
 	}
 
-	// Parse as regular sitemap
 	var urlSet URLSet
 	if err := xml.Unmarshal(body, &urlSet); err != nil {
-		return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
-	}
-
-	urls := make([]string, 0, len(urlSet.URLs))
-	for _, u := range urlSet.URLs {
-		urls = append(urls, u.Loc)
+		return nil, nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
 	}
 
-	result.Sitemaps[sitemapURL] = urls
-	result.AllURLs = dedupe(urls)
-
-	return result, nil
+	return &urlSet, nil, nil
 }
 
-// fetchSitemapIndexGrouped fetches all sitemaps and groups URLs by source
-func fetchSitemapIndexGrouped(client *http.Client, index SitemapIndex) (*FetchResult, error) {
-	result := &FetchResult{
-		Sitemaps: make(map[string][]string),
-	}
-
-	for _, sm := range index.Sitemaps {
-		req, err := http.NewRequest("GET", sm.Loc, nil)
-		if err != nil {
-			continue
-		}
-		req.Header.Set("Accept", "application/xml, text/xml")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			continue
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			continue
-		}
-
-		var urlSet URLSet
-		if err := xml.Unmarshal(body, &urlSet); err != nil {
-			continue
-		}
-
-		urls := make([]string, 0, len(urlSet.URLs))
-		for _, u := range urlSet.URLs {
-			urls = append(urls, u.Loc)
-		}
-
-		result.Sitemaps[sm.Loc] = urls
-		result.AllURLs = append(result.AllURLs, urls...)
-	}
-
-	result.AllURLs = dedupe(result.AllURLs)
-	return result, nil
+// lastModLayouts are the <lastmod> formats seen in the wild: full W3C
+// datetime, date-only, and RFC3339.
+var lastModLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-07:00",
+	"2006-01-02",
 }
 
-// fetchSitemapIndex fetches all sitemaps from a sitemap index
-func fetchSitemapIndex(client *http.Client, index SitemapIndex) ([]string, error) {
-	var allURLs []string
-
-	for _, sm := range index.Sitemaps {
-		req, err := http.NewRequest("GET", sm.Loc, nil)
-		if err != nil {
-			continue
-		}
-		req.Header.Set("Accept", "application/xml, text/xml")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			continue // Skip failed sitemaps
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			continue
-		}
-
-		var urlSet URLSet
-		if err := xml.Unmarshal(body, &urlSet); err != nil {
-			continue
-		}
-
-		for _, u := range urlSet.URLs {
-			allURLs = append(allURLs, u.Loc)
+func parseLastMod(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range lastModLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
 		}
 	}
-
-	return dedupe(allURLs), nil
+	return time.Time{}, false
 }
 
 // dedupe removes duplicate URLs
@@ -0,0 +1,111 @@
+package sitemap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSitemapBody_URLSet(t *testing.T) {
+	body := []byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<url><loc>https://example.com/a</loc><lastmod>2026-01-02</lastmod></url>
+<url><loc>https://example.com/b</loc></url>
+</urlset>`)
+
+	urlSet, index, err := parseSitemapBody(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if index != nil {
+		t.Fatalf("expected a urlset, got a sitemap index")
+	}
+	if len(urlSet.URLs) != 2 {
+		t.Fatalf("expected 2 URLs, got %d", len(urlSet.URLs))
+	}
+	if urlSet.URLs[0].Loc != "https://example.com/a" {
+		t.Errorf("unexpected loc: %s", urlSet.URLs[0].Loc)
+	}
+}
+
+func TestParseSitemapBody_Index(t *testing.T) {
+	body := []byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>https://example.com/sitemap1.xml</loc></sitemap>
+<sitemap><loc>https://example.com/sitemap2.xml</loc></sitemap>
+</sitemapindex>`)
+
+	urlSet, index, err := parseSitemapBody(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if urlSet != nil {
+		t.Fatalf("expected a sitemap index, got a urlset")
+	}
+	if len(index.Sitemaps) != 2 {
+		t.Fatalf("expected 2 child sitemaps, got %d", len(index.Sitemaps))
+	}
+}
+
+func TestParseSitemapBody_Invalid(t *testing.T) {
+	if _, _, err := parseSitemapBody([]byte("not xml")); err == nil {
+		t.Fatal("expected an error for invalid XML")
+	}
+}
+
+func TestParseLastMod(t *testing.T) {
+	cases := []struct {
+		value string
+		ok    bool
+	}{
+		{"2026-07-01", true},
+		{"2026-07-01T12:00:00Z", true},
+		{"2026-07-01T12:00:00-07:00", true},
+		{"", false},
+		{"not-a-date", false},
+	}
+
+	for _, c := range cases {
+		_, ok := parseLastMod(c.value)
+		if ok != c.ok {
+			t.Errorf("parseLastMod(%q) ok = %v, want %v", c.value, ok, c.ok)
+		}
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	in := []string{"a", "b", "a", "c", "b"}
+	want := []string{"a", "b", "c"}
+
+	got := dedupe(in)
+	if len(got) != len(want) {
+		t.Fatalf("dedupe(%v) = %v, want %v", in, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("dedupe(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestFetchResultMerge(t *testing.T) {
+	r := newFetchResult()
+	r.addSitemap("https://example.com/sitemap.xml", []string{"https://example.com/a"})
+
+	other := newFetchResult()
+	other.addSitemap("https://example.com/sitemap2.xml", []string{"https://example.com/b"})
+	other.setSource("https://example.com/sitemap2.xml", "robots.txt")
+	other.setLastMod("https://example.com/b", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	r.merge(other)
+
+	if len(r.Sitemaps) != 2 {
+		t.Fatalf("expected 2 sitemaps after merge, got %d", len(r.Sitemaps))
+	}
+	if r.Sources["https://example.com/sitemap2.xml"] != "robots.txt" {
+		t.Errorf("expected merged source to carry over, got %q", r.Sources["https://example.com/sitemap2.xml"])
+	}
+	if _, ok := r.LastMod["https://example.com/b"]; !ok {
+		t.Errorf("expected merged lastmod to carry over")
+	}
+	if len(r.AllURLs) != 2 {
+		t.Fatalf("expected 2 AllURLs after merge, got %d", len(r.AllURLs))
+	}
+}